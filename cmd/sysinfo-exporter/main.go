@@ -0,0 +1,39 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+// Command sysinfo-exporter serves /metrics (Prometheus) and /inventory.json
+// (the raw sysinfo.SysInfo) for the host it runs on.
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"log"
+	"net/http"
+
+	"github.com/tnaganawa/sysinfo"
+	"github.com/tnaganawa/sysinfo/exporter"
+)
+
+func main() {
+	addr := flag.String("listen", ":9922", "address to serve /metrics and /inventory.json on")
+	flag.Parse()
+
+	collect := func() *sysinfo.SysInfo {
+		si := sysinfo.NewSysInfo()
+		si.GetInfo()
+		return si
+	}
+
+	http.Handle("/metrics", exporter.Handler(exporter.New(collect)))
+	http.HandleFunc("/inventory.json", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(collect()); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	})
+
+	log.Printf("sysinfo-exporter listening on %s", *addr)
+	log.Fatal(http.ListenAndServe(*addr, nil))
+}
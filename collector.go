@@ -0,0 +1,18 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+// Collector knows how to populate the OS-specific parts of a SysInfo (CPU and
+// network details). There is one implementation per supported GOOS, selected
+// automatically at compile time by the sysinfo_<goos>.go file naming
+// convention; newHostCollector returns whichever one was built for the
+// current platform.
+type Collector interface {
+	// CollectCPU fills in si.CPU.
+	CollectCPU(si *SysInfo)
+
+	// CollectNetwork fills in si.Network.
+	CollectNetwork(si *SysInfo)
+}
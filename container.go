@@ -0,0 +1,17 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+// Container holds cgroup-derived resource limits, populated when the process
+// is confined by one, so SysInfo can report the container's view instead of
+// the host's — the same problem Node.Hypervisor solves for virtualization.
+type Container struct {
+	Runtime     string  `json:"runtime,omitempty"`    // docker, containerd, crio, podman, systemd, ...
+	CPUQuota    int64   `json:"cpu_quota,omitempty"`  // microseconds of CPU time allowed per CPUPeriod; -1 means unlimited
+	CPUPeriod   int64   `json:"cpu_period,omitempty"` // microseconds
+	CPUs        float64 `json:"cpus,omitempty"`       // effective CPU count, ceil(CPUQuota/CPUPeriod)
+	CPUSet      []int   `json:"cpu_set,omitempty"`
+	MemoryLimit int64   `json:"memory_limit,omitempty"` // bytes
+}
@@ -0,0 +1,253 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+import (
+	"bufio"
+	"math"
+	"os"
+	"path"
+	"strconv"
+	"strings"
+)
+
+// getContainerInfo detects whether the process is confined by a cgroup v1 or
+// v2 hierarchy and, if so, populates si.Container from it. The hierarchy is
+// located by walking /proc/self/cgroup and resolving the real mount point via
+// /proc/self/mountinfo, rather than assuming the process's own cgroup is
+// bind-mounted at the conventional /sys/fs/cgroup root — that assumption
+// holds for the default Docker/Kubernetes setup, but not for a cgroup-limited
+// process sharing the host's cgroup namespace (e.g. cgroupns=host, or a
+// systemd unit with CPUQuota=).
+func (si *SysInfo) getContainerInfo() {
+	if dir, ok := resolveCgroupV2Dir(); ok {
+		si.getContainerInfoV2(dir)
+	} else if dir, ok := resolveCgroupV1Dir("cpu"); ok {
+		si.getContainerInfoV1(dir)
+	} else {
+		return
+	}
+
+	si.Container.Runtime = detectContainerRuntime()
+}
+
+func (si *SysInfo) getContainerInfoV1(cpuDir string) {
+	quota, _ := strconv.ParseInt(slurpFile(cpuDir+"/cpu.cfs_quota_us"), 10, 64)
+	period, _ := strconv.ParseInt(slurpFile(cpuDir+"/cpu.cfs_period_us"), 10, 64)
+
+	si.Container.CPUQuota = quota
+	si.Container.CPUPeriod = period
+
+	if quota > 0 && period > 0 {
+		si.Container.CPUs = math.Ceil(float64(quota) / float64(period))
+	}
+
+	if cpusetDir, ok := resolveCgroupV1Dir("cpuset"); ok {
+		si.Container.CPUSet = parseCPUSet(slurpFile(cpusetDir + "/cpuset.cpus"))
+	}
+
+	if memDir, ok := resolveCgroupV1Dir("memory"); ok {
+		if limit, err := strconv.ParseInt(slurpFile(memDir+"/memory.limit_in_bytes"), 10, 64); err == nil {
+			si.Container.MemoryLimit = limit
+		}
+	}
+}
+
+func (si *SysInfo) getContainerInfoV2(dir string) {
+	if fields := strings.Fields(slurpFile(dir + "/cpu.max")); len(fields) == 2 {
+		period, _ := strconv.ParseInt(fields[1], 10, 64)
+		si.Container.CPUPeriod = period
+
+		if fields[0] == "max" {
+			si.Container.CPUQuota = -1
+		} else if quota, err := strconv.ParseInt(fields[0], 10, 64); err == nil {
+			si.Container.CPUQuota = quota
+			if period > 0 {
+				si.Container.CPUs = math.Ceil(float64(quota) / float64(period))
+			}
+		}
+	}
+
+	si.Container.CPUSet = parseCPUSet(slurpFile(dir + "/cpuset.cpus"))
+
+	if limit := slurpFile(dir + "/memory.max"); limit != "" && limit != "max" {
+		if v, err := strconv.ParseInt(limit, 10, 64); err == nil {
+			si.Container.MemoryLimit = v
+		}
+	}
+}
+
+// resolveCgroupV2Dir returns the process's own cgroup v2 directory, or false
+// if the process isn't under a (mounted) cgroup v2 hierarchy.
+func resolveCgroupV2Dir() (string, bool) {
+	cgPath, ok := selfCgroupPath("")
+	if !ok {
+		return "", false
+	}
+
+	mountPoint, root, ok := findCgroupMount("cgroup2", "")
+	if !ok {
+		return "", false
+	}
+
+	return joinCgroupPath(mountPoint, root, cgPath), true
+}
+
+// resolveCgroupV1Dir returns the process's own cgroup v1 directory for the
+// given controller (e.g. "cpu", "cpuset", "memory"), or false if that
+// controller isn't mounted, or the process isn't confined by it.
+func resolveCgroupV1Dir(controller string) (string, bool) {
+	cgPath, ok := selfCgroupPath(controller)
+	if !ok {
+		return "", false
+	}
+
+	mountPoint, root, ok := findCgroupMount("cgroup", controller)
+	if !ok {
+		return "", false
+	}
+
+	return joinCgroupPath(mountPoint, root, cgPath), true
+}
+
+// selfCgroupPath looks up the process's cgroup path from /proc/self/cgroup,
+// for the given v1 controller, or for the unified v2 hierarchy when
+// controller is "".
+func selfCgroupPath(controller string) (string, bool) {
+	for _, line := range strings.Split(slurpFile("/proc/self/cgroup"), "\n") {
+		// Format: hierarchy-ID:controller-list:cgroup-path, e.g.
+		// "4:cpu,cpuacct:/docker/<id>" (v1) or "0::/user.slice/..." (v2).
+		fields := strings.SplitN(line, ":", 3)
+		if len(fields) != 3 {
+			continue
+		}
+
+		if controller == "" {
+			if fields[1] == "" {
+				return fields[2], true
+			}
+			continue
+		}
+
+		for _, c := range strings.Split(fields[1], ",") {
+			if c == controller {
+				return fields[2], true
+			}
+		}
+	}
+
+	return "", false
+}
+
+// findCgroupMount scans /proc/self/mountinfo for the mount of the given
+// cgroup filesystem type, returning its mount point and the subtree of the
+// filesystem that's visible there (the "root" field). For fsType "cgroup",
+// controller selects the v1 hierarchy whose super options list it.
+func findCgroupMount(fsType, controller string) (mountPoint, root string, ok bool) {
+	f, err := os.Open("/proc/self/mountinfo")
+	if err != nil {
+		return "", "", false
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		// Format (proc(5)): ... mountID parentID major:minor root
+		// mountPoint options optionalFields* - fsType mountSource
+		// superOptions
+		fields := strings.Fields(s.Text())
+
+		sep := -1
+		for i, field := range fields {
+			if field == "-" {
+				sep = i
+				break
+			}
+		}
+		if sep < 0 || sep+1 >= len(fields) || fields[sep+1] != fsType {
+			continue
+		}
+
+		if fsType == "cgroup" {
+			superOptions := fields[len(fields)-1]
+			hasController := false
+			for _, o := range strings.Split(superOptions, ",") {
+				if o == controller {
+					hasController = true
+					break
+				}
+			}
+			if !hasController {
+				continue
+			}
+		}
+
+		return fields[4], fields[3], true
+	}
+
+	return "", "", false
+}
+
+// joinCgroupPath maps a cgroup path as seen in /proc/self/cgroup onto its
+// location on disk, accounting for mounts that only expose a subtree of the
+// hierarchy (root != "/") — the normal case inside a cgroup namespace.
+func joinCgroupPath(mountPoint, root, cgPath string) string {
+	rel := strings.TrimPrefix(cgPath, root)
+	if root != "/" && rel == cgPath {
+		// cgPath isn't under the mount's visible root, so the mount must
+		// already be scoped to (an ancestor of) our own cgroup; use it as-is.
+		rel = cgPath
+	}
+
+	return path.Join(mountPoint, rel)
+}
+
+// detectContainerRuntime infers the container runtime from the init
+// process's cgroup membership, the way `systemd-detect-virt --container`
+// and most container-aware monitoring agents do it.
+func detectContainerRuntime() string {
+	cgroup := slurpFile("/proc/1/cgroup")
+
+	switch {
+	case strings.Contains(cgroup, "docker"):
+		return "docker"
+	case strings.Contains(cgroup, "containerd"):
+		return "containerd"
+	case strings.Contains(cgroup, "crio"):
+		return "crio"
+	case strings.Contains(cgroup, "libpod"), strings.Contains(cgroup, "podman"):
+		return "podman"
+	case strings.Contains(cgroup, ".scope"):
+		return "systemd"
+	default:
+		return ""
+	}
+}
+
+// parseCPUSet expands a cgroup cpuset list such as "0-2,5" into [0, 1, 2, 5].
+func parseCPUSet(list string) []int {
+	list = strings.TrimSpace(list)
+	if list == "" {
+		return nil
+	}
+
+	var cpus []int
+	for _, part := range strings.Split(list, ",") {
+		if before, after, found := strings.Cut(part, "-"); found {
+			start, err1 := strconv.Atoi(before)
+			end, err2 := strconv.Atoi(after)
+			if err1 != nil || err2 != nil {
+				continue
+			}
+			for i := start; i <= end; i++ {
+				cpus = append(cpus, i)
+			}
+		} else if n, err := strconv.Atoi(part); err == nil {
+			cpus = append(cpus, n)
+		}
+	}
+
+	return cpus
+}
@@ -0,0 +1,27 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCPUSet(t *testing.T) {
+	assert.Equal(t, []int{0, 1, 2, 5}, parseCPUSet("0-2,5"), "CPU set don't match")
+	assert.Equal(t, []int{0}, parseCPUSet("0"), "single CPU set don't match")
+	assert.Nil(t, parseCPUSet(""), "empty list should yield a nil CPU set")
+	assert.Nil(t, parseCPUSet("not-a-cpuset"), "garbage input should yield a nil CPU set")
+}
+
+func TestDetectContainerRuntime(t *testing.T) {
+	// detectContainerRuntime reads the fixed path /proc/1/cgroup, so it can
+	// only be exercised against whatever that reports on the test machine;
+	// assert it at least returns one of the known values (including "" for
+	// "not containerized").
+	runtime := detectContainerRuntime()
+	assert.Contains(t, []string{"", "docker", "containerd", "crio", "podman", "systemd"}, runtime, "unexpected container runtime")
+}
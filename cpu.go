@@ -5,116 +5,81 @@
 package sysinfo
 
 import (
-	"bufio"
-	"fmt"
-	"os"
-	"regexp"
-	"runtime"
-	"strconv"
+	"encoding/json"
 	"strings"
 )
 
 // CPU information.
 type CPU struct {
-	Vendor  string `json:"vendor,omitempty"`
-	Model   string `json:"model,omitempty"`
-	Speed   uint   `json:"speed,omitempty"`   // CPU clock rate in MHz
-	Cache   uint   `json:"cache,omitempty"`   // CPU cache size in KB
-	Cpus    uint   `json:"cpus,omitempty"`    // number of physical CPUs
-	Cores   uint   `json:"cores,omitempty"`   // number of physical CPU cores
-	Threads uint   `json:"threads,omitempty"` // number of logical (HT) CPU cores
-	Flags   string  `json:"flags,omitempty"`  // CPU flags
-
+	Vendor   string   `json:"vendor,omitempty"`
+	Model    string   `json:"model,omitempty"`
+	Speed    uint     `json:"speed,omitempty"`   // CPU clock rate in MHz
+	Cache    uint     `json:"cache,omitempty"`   // CPU cache size in KB
+	Cpus     uint     `json:"cpus,omitempty"`    // number of physical CPUs
+	Cores    uint     `json:"cores,omitempty"`   // number of physical CPU cores
+	Threads  uint     `json:"threads,omitempty"` // number of logical (HT) CPU cores
+	Flags    Flags    `json:"flags,omitempty"`   // CPU flags
+	Features Features `json:"features,omitempty"`
 }
 
-var (
-	reTwoColumns = regexp.MustCompile("\t+: ")
-	reExtraSpace = regexp.MustCompile(" +")
-	reCacheSize  = regexp.MustCompile(`^(\d+) KB$`)
-	cpuInfo      = "/proc/cpuinfo"
-)
-
-func (si *SysInfo) getCPUInfo() {
-	si.CPU.Threads = uint(runtime.NumCPU())
+// Flags is the set of CPU feature flags reported by /proc/cpuinfo's "flags"
+// field on x86, "Features" on ARM, or "isa" on RISC-V.
+type Flags []string
 
-	f, err := os.Open(cpuInfo)
-	if err != nil {
-		return
+// UnmarshalJSON accepts both the current array encoding and the single
+// space-separated string that CPU.Flags used before this release, so
+// clients decoding payloads produced by either version keep working.
+func (f *Flags) UnmarshalJSON(data []byte) error {
+	var list []string
+	if err := json.Unmarshal(data, &list); err == nil {
+		*f = list
+		return nil
 	}
-	defer f.Close()
-
-	cpu := make(map[string]bool)
-	core := make(map[string]bool)
 
-	// for virtualized environment
-	cpuCount := 0
-	coreCount := 0
-
-	var cpuID string
-
-	s := bufio.NewScanner(f)
-	for s.Scan() {
-		//log.Printf("Line: %s", s.Text())
-		if sl := reTwoColumns.Split(s.Text(), 2); sl != nil {
-			switch sl[0] {
-			case "processor":
-				cpuID = sl[1]
-				cpu[cpuID] = true
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*f = strings.Fields(s)
+	return nil
+}
 
-				cpuCount += 1
-			case "core id":
-				coreID := fmt.Sprintf("%s/%s", cpuID, sl[1])
-				core[coreID] = true
-			case "cpu cores":
-				c, err := strconv.ParseInt(sl[1], 10, 8)
-				if err == nil {
-					coreCount += int(c)
-				}
-				coreCount += 1
-			case "vendor_id":
-				if si.CPU.Vendor == "" {
-					si.CPU.Vendor = sl[1]
-				}
-			case "flags":
-				if si.CPU.Flags == "" {
-					si.CPU.Flags = sl[1]
-				}
-			case "model name":
-				if si.CPU.Model == "" {
-					// CPU model, as reported by /proc/cpuinfo, can be a bit ugly. Clean up...
-					model := reExtraSpace.ReplaceAllLiteralString(sl[1], " ")
-					si.CPU.Model = strings.Replace(model, "- ", "-", 1)
-				}
-			case "cpu MHz":
-				if si.CPU.Speed == uint(0) {
-					i, err := strconv.ParseFloat(sl[1], 32)
-					if err == nil {
-						si.CPU.Speed = uint(i)
-					}
-				}
-			case "cache size":
-				if si.CPU.Cache == 0 {
-					if m := reCacheSize.FindStringSubmatch(sl[1]); m != nil {
-						if cache, err := strconv.ParseUint(m[1], 10, 64); err == nil {
-							si.CPU.Cache = uint(cache)
-						}
-					}
-				}
-			}
+// HasFlag reports whether the CPU advertises the named flag, matched
+// case-insensitively (e.g. "avx2", "sha_ni").
+func (c *CPU) HasFlag(name string) bool {
+	for _, flag := range c.Flags {
+		if strings.EqualFold(flag, name) {
+			return true
 		}
 	}
-	if s.Err() != nil {
-		return
-	}
+	return false
+}
 
-	// getNodeInfo() must have run first, to detect if we're dealing with a virtualized CPU! Detecting number of
-	// physical processors and/or cores is totally unreliable in virtualized environments, so let's not do it.
-	if si.Node.Hostname == "" || si.Node.Hypervisor != "" {
-		// fallback to counts when virtualized
-		si.CPU.Cpus = uint(cpuCount)
-		si.CPU.Cores = uint(coreCount)
-	}
+// Features holds the handful of ISA extensions callers most commonly need to
+// gate optimized code paths on, derived from CPU.Flags so they don't have to
+// know each architecture's flag spelling.
+type Features struct {
+	SSE4_2  bool `json:"sse4_2,omitempty"`
+	AVX     bool `json:"avx,omitempty"`
+	AVX2    bool `json:"avx2,omitempty"`
+	AVX512F bool `json:"avx512f,omitempty"`
+	AES     bool `json:"aes,omitempty"`
+	VMX     bool `json:"vmx,omitempty"`
+	SVM     bool `json:"svm,omitempty"`
+	SHA_NI  bool `json:"sha_ni,omitempty"`
+	RDRAND  bool `json:"rdrand,omitempty"`
+}
 
-	si.CPU.Cpus = uint(len(cpu))
-	si.CPU.Cores = uint(len(core))
+func newFeatures(c *CPU) Features {
+	return Features{
+		SSE4_2:  c.HasFlag("sse4_2"),
+		AVX:     c.HasFlag("avx"),
+		AVX2:    c.HasFlag("avx2"),
+		AVX512F: c.HasFlag("avx512f"),
+		AES:     c.HasFlag("aes"),
+		VMX:     c.HasFlag("vmx"),
+		SVM:     c.HasFlag("svm"),
+		SHA_NI:  c.HasFlag("sha_ni"),
+		RDRAND:  c.HasFlag("rdrand"),
+	}
 }
@@ -1,3 +1,5 @@
+//go:build linux
+
 package sysinfo
 
 import (
@@ -24,3 +26,37 @@ func TestCPUInfo(t *testing.T) {
 	assert.Equal(t, uint(2500), si.CPU.Speed, "Speed don't match")
 	assert.Equal(t, uint(2), si.CPU.Cpus, "Cpus don't match")
 }
+
+func TestHasFlag(t *testing.T) {
+	cpuInfoPrev := cpuInfo
+	defer func() { cpuInfo = cpuInfoPrev }()
+	cpuInfo = "testdata/cpuinfo"
+
+	si := &SysInfo{}
+	si.getCPUInfo()
+
+	assert.True(t, si.CPU.HasFlag("avx2"), "expected avx2 flag to be present")
+	assert.True(t, si.CPU.HasFlag("AVX2"), "HasFlag should be case-insensitive")
+	assert.False(t, si.CPU.HasFlag("vmx"), "vmx flag isn't in the fixture")
+}
+
+func TestFeatures(t *testing.T) {
+	cpuInfoPrev := cpuInfo
+	defer func() { cpuInfo = cpuInfoPrev }()
+	cpuInfo = "testdata/cpuinfo"
+
+	si := &SysInfo{}
+	si.getCPUInfo()
+
+	assert.Equal(t, Features{
+		SSE4_2:  true,
+		AVX:     true,
+		AVX2:    true,
+		AVX512F: true,
+		AES:     true,
+		VMX:     false,
+		SVM:     false,
+		SHA_NI:  false,
+		RDRAND:  true,
+	}, si.CPU.Features, "Features don't match")
+}
@@ -0,0 +1,80 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+// CPUTimes holds the raw jiffy counters for one CPU, as reported by
+// /proc/stat. CPU is either "cpu" for the aggregate of all CPUs, or "cpuN"
+// for an individual core.
+type CPUTimes struct {
+	CPU       string `json:"cpu"`
+	User      uint64 `json:"user"`
+	Nice      uint64 `json:"nice"`
+	System    uint64 `json:"system"`
+	Idle      uint64 `json:"idle"`
+	IOWait    uint64 `json:"iowait"`
+	IRQ       uint64 `json:"irq"`
+	SoftIRQ   uint64 `json:"softirq"`
+	Steal     uint64 `json:"steal"`
+	Guest     uint64 `json:"guest"`
+	GuestNice uint64 `json:"guest_nice"`
+}
+
+// Total returns the sum of all counters, i.e. the number of jiffies this CPU
+// has accounted for since boot.
+func (t CPUTimes) Total() uint64 {
+	return t.User + t.Nice + t.System + t.Idle + t.IOWait + t.IRQ + t.SoftIRQ + t.Steal + t.Guest + t.GuestNice
+}
+
+// CPUTimesPercent is the utilization breakdown, in percent of elapsed time,
+// between two CPUTimes snapshots.
+type CPUTimesPercent struct {
+	CPU     string  `json:"cpu"`
+	User    float64 `json:"user"`
+	Nice    float64 `json:"nice"`
+	System  float64 `json:"system"`
+	Idle    float64 `json:"idle"`
+	IOWait  float64 `json:"iowait"`
+	IRQ     float64 `json:"irq"`
+	SoftIRQ float64 `json:"softirq"`
+	Steal   float64 `json:"steal"`
+	Guest   float64 `json:"guest"`
+}
+
+// CPUStats holds system-wide scheduler counters that complement CPUTimes:
+// load average and cumulative context-switch, interrupt and process-creation
+// counts, all read from /proc/loadavg and /proc/stat.
+type CPUStats struct {
+	LoadAvg1         float64 `json:"load_avg_1,omitempty"`
+	LoadAvg5         float64 `json:"load_avg_5,omitempty"`
+	LoadAvg15        float64 `json:"load_avg_15,omitempty"`
+	ContextSwitches  uint64  `json:"context_switches,omitempty"`
+	Interrupts       uint64  `json:"interrupts,omitempty"`
+	SoftIRQs         uint64  `json:"soft_irqs,omitempty"`
+	ProcessesCreated uint64  `json:"processes_created,omitempty"`
+}
+
+func percentOf(prev, cur uint64, elapsed float64) float64 {
+	if cur < prev || elapsed <= 0 {
+		return 0
+	}
+	return float64(cur-prev) / elapsed * 100
+}
+
+func cpuTimesPercent(prev, cur CPUTimes) CPUTimesPercent {
+	elapsed := float64(cur.Total() - prev.Total())
+
+	return CPUTimesPercent{
+		CPU:     cur.CPU,
+		User:    percentOf(prev.User, cur.User, elapsed),
+		Nice:    percentOf(prev.Nice, cur.Nice, elapsed),
+		System:  percentOf(prev.System, cur.System, elapsed),
+		Idle:    percentOf(prev.Idle, cur.Idle, elapsed),
+		IOWait:  percentOf(prev.IOWait, cur.IOWait, elapsed),
+		IRQ:     percentOf(prev.IRQ, cur.IRQ, elapsed),
+		SoftIRQ: percentOf(prev.SoftIRQ, cur.SoftIRQ, elapsed),
+		Steal:   percentOf(prev.Steal, cur.Steal, elapsed),
+		Guest:   percentOf(prev.Guest, cur.Guest, elapsed),
+	}
+}
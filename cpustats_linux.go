@@ -0,0 +1,129 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+import (
+	"bufio"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	procStat    = "/proc/stat"
+	procLoadAvg = "/proc/loadavg"
+)
+
+// ReadCPUTimes reads /proc/stat and returns the raw jiffy counters for the
+// aggregate of all CPUs ("cpu") followed by each individual core ("cpu0",
+// "cpu1", ...). Callers that want utilization percentages should take two
+// snapshots apart in time and diff them, or just call SampleCPU.
+func ReadCPUTimes() ([]CPUTimes, error) {
+	f, err := os.Open(procStat)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var times []CPUTimes
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) == 0 || !strings.HasPrefix(fields[0], "cpu") {
+			continue
+		}
+
+		t := CPUTimes{CPU: fields[0]}
+		counters := []*uint64{&t.User, &t.Nice, &t.System, &t.Idle, &t.IOWait, &t.IRQ, &t.SoftIRQ, &t.Steal, &t.Guest, &t.GuestNice}
+		for i, v := range fields[1:] {
+			if i >= len(counters) {
+				break
+			}
+			n, err := strconv.ParseUint(v, 10, 64)
+			if err != nil {
+				break
+			}
+			*counters[i] = n
+		}
+
+		times = append(times, t)
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+
+	return times, nil
+}
+
+// SampleCPU takes two ReadCPUTimes snapshots interval apart and returns the
+// per-CPU (and aggregate) utilization breakdown between them.
+func (si *SysInfo) SampleCPU(interval time.Duration) ([]CPUTimesPercent, error) {
+	before, err := ReadCPUTimes()
+	if err != nil {
+		return nil, err
+	}
+
+	time.Sleep(interval)
+
+	after, err := ReadCPUTimes()
+	if err != nil {
+		return nil, err
+	}
+
+	percents := make([]CPUTimesPercent, 0, len(after))
+	for _, cur := range after {
+		for _, prev := range before {
+			if prev.CPU == cur.CPU {
+				percents = append(percents, cpuTimesPercent(prev, cur))
+				break
+			}
+		}
+	}
+
+	return percents, nil
+}
+
+// ReadCPUStats reads load average from /proc/loadavg and the cumulative
+// scheduler counters from /proc/stat.
+func ReadCPUStats() (CPUStats, error) {
+	var stats CPUStats
+
+	if data, err := os.ReadFile(procLoadAvg); err == nil {
+		if fields := strings.Fields(string(data)); len(fields) >= 3 {
+			stats.LoadAvg1, _ = strconv.ParseFloat(fields[0], 64)
+			stats.LoadAvg5, _ = strconv.ParseFloat(fields[1], 64)
+			stats.LoadAvg15, _ = strconv.ParseFloat(fields[2], 64)
+		}
+	}
+
+	f, err := os.Open(procStat)
+	if err != nil {
+		return stats, err
+	}
+	defer f.Close()
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 2 {
+			continue
+		}
+
+		switch fields[0] {
+		case "ctxt":
+			stats.ContextSwitches, _ = strconv.ParseUint(fields[1], 10, 64)
+		case "intr":
+			stats.Interrupts, _ = strconv.ParseUint(fields[1], 10, 64)
+		case "softirq":
+			stats.SoftIRQs, _ = strconv.ParseUint(fields[1], 10, 64)
+		case "processes":
+			stats.ProcessesCreated, _ = strconv.ParseUint(fields[1], 10, 64)
+		}
+	}
+
+	return stats, s.Err()
+}
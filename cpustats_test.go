@@ -0,0 +1,30 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPercentOf(t *testing.T) {
+	assert.Equal(t, 25.0, percentOf(100, 200, 400), "busy fraction of elapsed jiffies don't match")
+	assert.Equal(t, 0.0, percentOf(100, 100, 400), "no progress should be 0%")
+	assert.Equal(t, 0.0, percentOf(200, 100, 400), "counter going backwards should be clamped to 0%")
+	assert.Equal(t, 0.0, percentOf(100, 200, 0), "zero elapsed time should be clamped to 0%")
+}
+
+func TestCPUTimesPercent(t *testing.T) {
+	prev := CPUTimes{CPU: "cpu0", User: 100, Nice: 0, System: 50, Idle: 800, IOWait: 10, IRQ: 0, SoftIRQ: 0, Steal: 0, Guest: 0, GuestNice: 0}
+	cur := CPUTimes{CPU: "cpu0", User: 150, Nice: 0, System: 100, Idle: 800, IOWait: 10, IRQ: 0, SoftIRQ: 0, Steal: 0, Guest: 0, GuestNice: 0}
+
+	pct := cpuTimesPercent(prev, cur)
+
+	assert.Equal(t, "cpu0", pct.CPU, "CPU label don't match")
+	assert.Equal(t, 50.0, pct.User, "User percentage don't match")
+	assert.Equal(t, 50.0, pct.System, "System percentage don't match")
+	assert.Equal(t, 0.0, pct.Idle, "Idle percentage don't match")
+}
@@ -0,0 +1,75 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+// Package exporter exposes a sysinfo.SysInfo as a prometheus.Collector, so it
+// can be scraped by Prometheus (or anything speaking its exposition format,
+// like Telegraf) without every consumer re-implementing the field mapping.
+package exporter
+
+import (
+	"github.com/prometheus/client_golang/prometheus"
+
+	"github.com/tnaganawa/sysinfo"
+)
+
+// Exporter collects metrics from a SysInfo that it refreshes on every scrape.
+type Exporter struct {
+	collect func() *sysinfo.SysInfo
+
+	cpuSpeed        *prometheus.Desc
+	cpuCores        *prometheus.Desc
+	cpuThreads      *prometheus.Desc
+	networkSpeed    *prometheus.Desc
+	networkRxBytes  *prometheus.Desc
+	networkTxBytes  *prometheus.Desc
+	networkRxErrors *prometheus.Desc
+	networkTxErrors *prometheus.Desc
+}
+
+// New returns an Exporter that calls collect to get a freshly populated
+// SysInfo on every Collect (i.e. every scrape).
+func New(collect func() *sysinfo.SysInfo) *Exporter {
+	return &Exporter{
+		collect: collect,
+
+		cpuSpeed:   prometheus.NewDesc("sysinfo_cpu_speed_mhz", "CPU clock rate in MHz.", nil, nil),
+		cpuCores:   prometheus.NewDesc("sysinfo_cpu_cores", "Number of physical CPU cores.", nil, nil),
+		cpuThreads: prometheus.NewDesc("sysinfo_cpu_threads", "Number of logical (HT) CPU cores.", nil, nil),
+
+		networkSpeed:    prometheus.NewDesc("sysinfo_network_device_speed_mbps", "Current negotiated link speed in Mbps.", []string{"device", "driver"}, nil),
+		networkRxBytes:  prometheus.NewDesc("sysinfo_network_rx_bytes_total", "Received bytes.", []string{"device"}, nil),
+		networkTxBytes:  prometheus.NewDesc("sysinfo_network_tx_bytes_total", "Transmitted bytes.", []string{"device"}, nil),
+		networkRxErrors: prometheus.NewDesc("sysinfo_network_rx_errors_total", "Receive errors.", []string{"device"}, nil),
+		networkTxErrors: prometheus.NewDesc("sysinfo_network_tx_errors_total", "Transmit errors.", []string{"device"}, nil),
+	}
+}
+
+// Describe implements prometheus.Collector.
+func (e *Exporter) Describe(ch chan<- *prometheus.Desc) {
+	ch <- e.cpuSpeed
+	ch <- e.cpuCores
+	ch <- e.cpuThreads
+	ch <- e.networkSpeed
+	ch <- e.networkRxBytes
+	ch <- e.networkTxBytes
+	ch <- e.networkRxErrors
+	ch <- e.networkTxErrors
+}
+
+// Collect implements prometheus.Collector.
+func (e *Exporter) Collect(ch chan<- prometheus.Metric) {
+	si := e.collect()
+
+	ch <- prometheus.MustNewConstMetric(e.cpuSpeed, prometheus.GaugeValue, float64(si.CPU.Speed))
+	ch <- prometheus.MustNewConstMetric(e.cpuCores, prometheus.GaugeValue, float64(si.CPU.Cores))
+	ch <- prometheus.MustNewConstMetric(e.cpuThreads, prometheus.GaugeValue, float64(si.CPU.Threads))
+
+	for _, dev := range si.Network {
+		ch <- prometheus.MustNewConstMetric(e.networkSpeed, prometheus.GaugeValue, float64(dev.CurrentSpeed), dev.Name, dev.Driver)
+		ch <- prometheus.MustNewConstMetric(e.networkRxBytes, prometheus.CounterValue, float64(dev.Stats.RxBytes), dev.Name)
+		ch <- prometheus.MustNewConstMetric(e.networkTxBytes, prometheus.CounterValue, float64(dev.Stats.TxBytes), dev.Name)
+		ch <- prometheus.MustNewConstMetric(e.networkRxErrors, prometheus.CounterValue, float64(dev.Stats.RxErrors), dev.Name)
+		ch <- prometheus.MustNewConstMetric(e.networkTxErrors, prometheus.CounterValue, float64(dev.Stats.TxErrors), dev.Name)
+	}
+}
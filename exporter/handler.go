@@ -0,0 +1,22 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package exporter
+
+import (
+	"net/http"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Handler registers e with its own registry and returns an http.Handler that
+// serves its metrics in the Prometheus exposition format, ready to mount at
+// /metrics.
+func Handler(e *Exporter) http.Handler {
+	registry := prometheus.NewRegistry()
+	registry.MustRegister(e)
+
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
@@ -0,0 +1,64 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+import (
+	"encoding/json"
+
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/structpb"
+	"sigs.k8s.io/yaml"
+)
+
+// ToYAML serializes si as YAML. It reuses si's JSON struct tags for field
+// names (via sigs.k8s.io/yaml), so it stays in lockstep with the JSON shape
+// without a parallel set of `yaml:` tags to maintain.
+func (si *SysInfo) ToYAML() ([]byte, error) {
+	return yaml.Marshal(si)
+}
+
+// FromYAML populates si from YAML produced by ToYAML, or any YAML document
+// whose keys match si's JSON field names.
+func FromYAML(data []byte, si *SysInfo) error {
+	return yaml.Unmarshal(data, si)
+}
+
+// ToProto serializes si as a protobuf-encoded google.protobuf.Struct, built
+// from its JSON representation. This lets sysinfo drop into gRPC-based
+// inventory services without a hand-maintained .proto schema to keep in sync
+// as fields are added.
+func (si *SysInfo) ToProto() ([]byte, error) {
+	data, err := json.Marshal(si)
+	if err != nil {
+		return nil, err
+	}
+
+	var m map[string]interface{}
+	if err := json.Unmarshal(data, &m); err != nil {
+		return nil, err
+	}
+
+	s, err := structpb.NewStruct(m)
+	if err != nil {
+		return nil, err
+	}
+
+	return proto.Marshal(s)
+}
+
+// FromProto populates si from protobuf bytes produced by ToProto.
+func FromProto(data []byte, si *SysInfo) error {
+	var s structpb.Struct
+	if err := proto.Unmarshal(data, &s); err != nil {
+		return err
+	}
+
+	raw, err := json.Marshal(s.AsMap())
+	if err != nil {
+		return err
+	}
+
+	return json.Unmarshal(raw, si)
+}
@@ -0,0 +1,50 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func testSysInfo() *SysInfo {
+	return &SysInfo{
+		Node: Node{Hostname: "host1", Hypervisor: "kvm"},
+		CPU: CPU{
+			Vendor:  "GenuineIntel",
+			Model:   "Intel(R) Xeon(R) Platinum 8175M CPU @ 2.50GHz",
+			Speed:   2500,
+			Cores:   4,
+			Threads: 8,
+			Flags:   Flags{"fpu", "vme"},
+		},
+		Network: []NetworkDevice{
+			{Name: "eth0", Speed: 10000, CurrentSpeed: 1000},
+		},
+	}
+}
+
+func TestYAMLRoundTrip(t *testing.T) {
+	si := testSysInfo()
+
+	data, err := si.ToYAML()
+	assert.NoError(t, err, "ToYAML should not fail")
+
+	var got SysInfo
+	assert.NoError(t, FromYAML(data, &got), "FromYAML should not fail")
+	assert.Equal(t, *si, got, "SysInfo don't survive a YAML round trip")
+}
+
+func TestProtoRoundTrip(t *testing.T) {
+	si := testSysInfo()
+
+	data, err := si.ToProto()
+	assert.NoError(t, err, "ToProto should not fail")
+
+	var got SysInfo
+	assert.NoError(t, FromProto(data, &got), "FromProto should not fail")
+	assert.Equal(t, *si, got, "SysInfo don't survive a protobuf round trip")
+}
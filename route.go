@@ -0,0 +1,22 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+// Route is one entry in the IPv4 or IPv6 routing table.
+type Route struct {
+	Interface   string `json:"interface"`
+	Destination string `json:"destination"`
+	Gateway     string `json:"gateway,omitempty"`
+	Genmask     string `json:"genmask,omitempty"`
+	Metric      int    `json:"metric,omitempty"`
+}
+
+// Neighbor is one entry in the IPv4 ARP neighbor table.
+type Neighbor struct {
+	Interface  string `json:"interface"`
+	IPAddress  string `json:"ip_address"`
+	MACAddress string `json:"mac_address,omitempty"`
+	State      string `json:"state,omitempty"`
+}
@@ -0,0 +1,142 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// ReadRoutes reads the IPv4 routing table from /proc/net/route.
+func ReadRoutes() ([]Route, error) {
+	f, err := os.Open("/proc/net/route")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var routes []Route
+
+	s := bufio.NewScanner(f)
+	s.Scan() // header line
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 8 {
+			continue
+		}
+
+		metric, _ := strconv.Atoi(fields[6])
+		routes = append(routes, Route{
+			Interface:   fields[0],
+			Destination: hexToIPv4(fields[1]).String(),
+			Gateway:     hexToIPv4(fields[2]).String(),
+			Genmask:     hexToIPv4(fields[7]).String(),
+			Metric:      metric,
+		})
+	}
+
+	return routes, s.Err()
+}
+
+// ReadRoutes6 reads the IPv6 routing table from /proc/net/ipv6_route.
+func ReadRoutes6() ([]Route, error) {
+	f, err := os.Open("/proc/net/ipv6_route")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var routes []Route
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 10 {
+			continue
+		}
+
+		prefixLen, _ := strconv.ParseUint(fields[1], 16, 8)
+		metric, _ := strconv.ParseInt(fields[5], 16, 64)
+		routes = append(routes, Route{
+			Interface:   fields[9],
+			Destination: fmt.Sprintf("%s/%d", hexToIPv6(fields[0]), prefixLen),
+			Gateway:     hexToIPv6(fields[4]).String(),
+			Metric:      int(metric),
+		})
+	}
+
+	return routes, s.Err()
+}
+
+// ReadNeighbors reads the IPv4 ARP table from /proc/net/arp. The kernel
+// doesn't expose IPv6 NDP neighbors through an equivalent procfs table; that
+// requires a netlink NDP dump, which is out of scope here.
+func ReadNeighbors() ([]Neighbor, error) {
+	f, err := os.Open("/proc/net/arp")
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var neighbors []Neighbor
+
+	s := bufio.NewScanner(f)
+	s.Scan() // header line
+	for s.Scan() {
+		fields := strings.Fields(s.Text())
+		if len(fields) < 6 {
+			continue
+		}
+
+		flags, _ := strconv.ParseUint(strings.TrimPrefix(fields[2], "0x"), 16, 8)
+		neighbors = append(neighbors, Neighbor{
+			IPAddress:  fields[0],
+			MACAddress: fields[3],
+			State:      arpState(flags),
+			Interface:  fields[5],
+		})
+	}
+
+	return neighbors, s.Err()
+}
+
+func arpState(flags uint64) string {
+	switch {
+	case flags&0x4 != 0:
+		return "permanent"
+	case flags&0x2 != 0:
+		return "reachable"
+	default:
+		return "incomplete"
+	}
+}
+
+func hexToIPv4(hex string) net.IP {
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil
+	}
+
+	ip := make(net.IP, 4)
+	binary.LittleEndian.PutUint32(ip, uint32(v))
+	return ip
+}
+
+func hexToIPv6(hex string) net.IP {
+	ip := make(net.IP, 16)
+	for i := 0; i < 16 && i*2+2 <= len(hex); i++ {
+		b, err := strconv.ParseUint(hex[i*2:i*2+2], 16, 8)
+		if err != nil {
+			return nil
+		}
+		ip[i] = byte(b)
+	}
+	return ip
+}
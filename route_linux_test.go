@@ -0,0 +1,29 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHexToIPv4(t *testing.T) {
+	// /proc/net/route encodes addresses as little-endian hex.
+	assert.Equal(t, "192.168.0.1", hexToIPv4("0100A8C0").String(), "IPv4 address don't match")
+	assert.Nil(t, hexToIPv4("not-hex"), "invalid hex should yield a nil IP")
+}
+
+func TestHexToIPv6(t *testing.T) {
+	hex := strings.Repeat("00", 15) + "01"
+	assert.Equal(t, "::1", hexToIPv6(hex).String(), "IPv6 address don't match")
+}
+
+func TestArpState(t *testing.T) {
+	assert.Equal(t, "permanent", arpState(0x4), "ATF_PERM flag don't match")
+	assert.Equal(t, "reachable", arpState(0x2), "ATF_COM flag don't match")
+	assert.Equal(t, "incomplete", arpState(0x0), "no flags don't match")
+}
@@ -0,0 +1,57 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+// SysInfo combines all the information about the host that this module is able to retrieve.
+type SysInfo struct {
+	Node      Node            `json:"node,omitempty"`
+	CPU       CPU             `json:"cpu,omitempty"`
+	CPUStats  CPUStats        `json:"cpu_stats,omitempty"`
+	Container Container       `json:"container,omitempty"`
+	Network   []NetworkDevice `json:"network,omitempty"`
+
+	collector Collector
+}
+
+// Node information.
+type Node struct {
+	Hostname   string `json:"hostname,omitempty"`
+	Hypervisor string `json:"hypervisor,omitempty"` // hypervisor type, when running in a virtualized environment
+}
+
+// Option configures a SysInfo before it collects any information.
+type Option func(*SysInfo)
+
+// WithCollector overrides the Collector that GetInfo uses, bypassing the OS
+// autodetection normally baked in at compile time via build tags. It exists
+// mainly so tests can exercise one platform's collection logic while running
+// on another.
+func WithCollector(c Collector) Option {
+	return func(si *SysInfo) {
+		si.collector = c
+	}
+}
+
+// NewSysInfo returns a SysInfo ready to have GetInfo called on it, using the
+// Collector for the current GOOS unless overridden with WithCollector.
+func NewSysInfo(opts ...Option) *SysInfo {
+	si := &SysInfo{collector: newHostCollector()}
+
+	for _, opt := range opts {
+		opt(si)
+	}
+
+	return si
+}
+
+// GetInfo populates si with information gathered by its Collector.
+func (si *SysInfo) GetInfo() {
+	if si.collector == nil {
+		si.collector = newHostCollector()
+	}
+
+	si.collector.CollectCPU(si)
+	si.collector.CollectNetwork(si)
+}
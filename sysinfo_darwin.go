@@ -0,0 +1,117 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+import (
+	"net"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// darwinCollector gathers CPU and network information via sysctl(8), the
+// same source gopsutil draws from on macOS.
+type darwinCollector struct{}
+
+func newHostCollector() Collector {
+	return darwinCollector{}
+}
+
+func (darwinCollector) CollectCPU(si *SysInfo) {
+	si.getCPUInfo()
+}
+
+func (darwinCollector) CollectNetwork(si *SysInfo) {
+	si.getNetworkInfo()
+}
+
+func (si *SysInfo) getCPUInfo() {
+	if model, err := unix.Sysctl("machdep.cpu.brand_string"); err == nil {
+		si.CPU.Model = strings.TrimSpace(model)
+	}
+
+	if vendor, err := unix.Sysctl("machdep.cpu.vendor"); err == nil {
+		si.CPU.Vendor = strings.TrimSpace(vendor)
+	}
+
+	if freq, err := unix.SysctlUint64("hw.cpufrequency"); err == nil {
+		si.CPU.Speed = uint(freq / 1000000)
+	}
+
+	if cache, err := unix.SysctlUint32("hw.l2cachesize"); err == nil {
+		si.CPU.Cache = uint(cache / 1024)
+	}
+
+	if cpus, err := unix.SysctlUint32("hw.packages"); err == nil {
+		si.CPU.Cpus = uint(cpus)
+	}
+
+	if cores, err := unix.SysctlUint32("hw.physicalcpu"); err == nil {
+		si.CPU.Cores = uint(cores)
+	}
+
+	if threads, err := unix.SysctlUint32("hw.logicalcpu"); err == nil {
+		si.CPU.Threads = uint(threads)
+	}
+
+	var rawFlags []string
+	if flags, err := unix.Sysctl("machdep.cpu.features"); err == nil {
+		rawFlags = append(rawFlags, strings.Fields(flags)...)
+	}
+	// AVX2, BMI1, BMI2 and ADX aren't in machdep.cpu.features; the kernel
+	// reports them separately, under the CPUID leaf 7 sysctl.
+	if flags, err := unix.Sysctl("machdep.cpu.leaf7_features"); err == nil {
+		rawFlags = append(rawFlags, strings.Fields(flags)...)
+	}
+
+	if len(rawFlags) > 0 {
+		flags := make(Flags, len(rawFlags))
+		for i, flag := range rawFlags {
+			flags[i] = normalizeDarwinFlag(flag)
+		}
+		si.CPU.Flags = flags
+	}
+
+	si.CPU.Features = newFeatures(&si.CPU)
+}
+
+// normalizeDarwinFlag maps a macOS machdep.cpu.features/leaf7_features token
+// (e.g. "SSE4.2", "AVX1.0") onto the canonical x86 flag spelling HasFlag
+// expects (e.g. "sse4_2", "avx"), so feature detection matches across
+// platforms.
+func normalizeDarwinFlag(flag string) string {
+	flag = strings.ToLower(flag)
+	flag = strings.ReplaceAll(flag, ".", "_")
+	if flag == "avx1_0" {
+		return "avx"
+	}
+	return flag
+}
+
+func (si *SysInfo) getNetworkInfo() {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return
+	}
+
+	si.Network = make([]NetworkDevice, 0, len(ifaces))
+	for _, iface := range ifaces {
+		device := NetworkDevice{
+			Name:       iface.Name,
+			MACAddress: iface.HardwareAddr.String(),
+		}
+
+		if addrs, err := iface.Addrs(); err == nil {
+			for _, addr := range addrs {
+				device.IpAddresses = append(device.IpAddresses, addr.String())
+			}
+		}
+
+		// Link speed isn't available through sysctl; it requires a SIOCGIFMEDIA
+		// ioctl that isn't worth the complexity until a caller actually needs it.
+
+		si.Network = append(si.Network, device)
+	}
+}
@@ -0,0 +1,67 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+import (
+	"net"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+// freebsdCollector gathers CPU and network information via sysctl(3).
+type freebsdCollector struct{}
+
+func newHostCollector() Collector {
+	return freebsdCollector{}
+}
+
+func (freebsdCollector) CollectCPU(si *SysInfo) {
+	si.getCPUInfo()
+}
+
+func (freebsdCollector) CollectNetwork(si *SysInfo) {
+	si.getNetworkInfo()
+}
+
+func (si *SysInfo) getCPUInfo() {
+	if model, err := unix.Sysctl("hw.model"); err == nil {
+		si.CPU.Model = strings.TrimSpace(model)
+	}
+
+	if freq, err := unix.SysctlUint32("dev.cpu.0.freq"); err == nil {
+		si.CPU.Speed = uint(freq)
+	}
+
+	if cpus, err := unix.SysctlUint32("kern.smp.cpus"); err == nil {
+		si.CPU.Threads = uint(cpus)
+	}
+
+	si.CPU.Cpus = 1
+	si.CPU.Cores = si.CPU.Threads
+}
+
+func (si *SysInfo) getNetworkInfo() {
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		return
+	}
+
+	si.Network = make([]NetworkDevice, 0, len(ifaces))
+	for _, iface := range ifaces {
+		device := NetworkDevice{
+			Name:       iface.Name,
+			MACAddress: iface.HardwareAddr.String(),
+		}
+
+		if addrs, err := iface.Addrs(); err == nil {
+			for _, addr := range addrs {
+				device.IpAddresses = append(device.IpAddresses, addr.String())
+			}
+		}
+
+		si.Network = append(si.Network, device)
+	}
+}
@@ -0,0 +1,543 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"path"
+	"regexp"
+	"runtime"
+	"strconv"
+	"strings"
+	"syscall"
+	"unsafe"
+)
+
+// linuxCollector gathers CPU and network information from /proc and /sys, the
+// way this package has always done on Linux.
+type linuxCollector struct{}
+
+func newHostCollector() Collector {
+	return linuxCollector{}
+}
+
+func (linuxCollector) CollectCPU(si *SysInfo) {
+	si.getContainerInfo()
+	si.getCPUInfo()
+	si.CPUStats, _ = ReadCPUStats()
+}
+
+func (linuxCollector) CollectNetwork(si *SysInfo) {
+	si.getNetworkInfo()
+}
+
+var (
+	reTwoColumns = regexp.MustCompile("\t+: ")
+	reExtraSpace = regexp.MustCompile(" +")
+	reCacheSize  = regexp.MustCompile(`^(\d+) KB$`)
+	cpuInfo      = "/proc/cpuinfo"
+)
+
+func (si *SysInfo) getCPUInfo() {
+	si.CPU.Threads = uint(runtime.NumCPU())
+
+	f, err := os.Open(cpuInfo)
+	if err != nil {
+		return
+	}
+	defer f.Close()
+
+	cpu := make(map[string]bool)
+	core := make(map[string]bool)
+
+	// for virtualized environment
+	cpuCount := 0
+	coreCount := 0
+
+	var cpuID string
+
+	s := bufio.NewScanner(f)
+	for s.Scan() {
+		//log.Printf("Line: %s", s.Text())
+		if sl := reTwoColumns.Split(s.Text(), 2); sl != nil {
+			switch sl[0] {
+			case "processor":
+				cpuID = sl[1]
+				cpu[cpuID] = true
+
+				cpuCount += 1
+			case "core id":
+				coreID := fmt.Sprintf("%s/%s", cpuID, sl[1])
+				core[coreID] = true
+			case "cpu cores":
+				c, err := strconv.ParseInt(sl[1], 10, 8)
+				if err == nil {
+					coreCount += int(c)
+				}
+				coreCount += 1
+			case "vendor_id":
+				if si.CPU.Vendor == "" {
+					si.CPU.Vendor = sl[1]
+				}
+			case "flags", "Features", "isa":
+				// "flags" is the x86 field name, "Features" is ARM's, "isa" is RISC-V's.
+				if len(si.CPU.Flags) == 0 {
+					si.CPU.Flags = Flags(strings.Fields(sl[1]))
+				}
+			case "model name":
+				if si.CPU.Model == "" {
+					// CPU model, as reported by /proc/cpuinfo, can be a bit ugly. Clean up...
+					model := reExtraSpace.ReplaceAllLiteralString(sl[1], " ")
+					si.CPU.Model = strings.Replace(model, "- ", "-", 1)
+				}
+			case "cpu MHz":
+				if si.CPU.Speed == uint(0) {
+					i, err := strconv.ParseFloat(sl[1], 32)
+					if err == nil {
+						si.CPU.Speed = uint(i)
+					}
+				}
+			case "cache size":
+				if si.CPU.Cache == 0 {
+					if m := reCacheSize.FindStringSubmatch(sl[1]); m != nil {
+						if cache, err := strconv.ParseUint(m[1], 10, 64); err == nil {
+							si.CPU.Cache = uint(cache)
+						}
+					}
+				}
+			}
+		}
+	}
+	if s.Err() != nil {
+		return
+	}
+
+	// getNodeInfo() must have run first, to detect if we're dealing with a virtualized CPU! Detecting number of
+	// physical processors and/or cores is totally unreliable in virtualized environments, so let's not do it.
+	if si.Node.Hostname == "" || si.Node.Hypervisor != "" {
+		// fallback to counts when virtualized
+		si.CPU.Cpus = uint(cpuCount)
+		si.CPU.Cores = uint(coreCount)
+	}
+
+	si.CPU.Cpus = uint(len(cpu))
+	si.CPU.Cores = uint(len(core))
+
+	// getContainerInfo() must have run first: a cpuset restricts us to a subset
+	// of the host's cores, and reporting the host's full count would be misleading.
+	if cpuset := len(si.Container.CPUSet); cpuset > 0 {
+		si.CPU.Cores = uint(cpuset)
+		si.CPU.Threads = uint(cpuset)
+	}
+
+	si.CPU.Features = newFeatures(&si.CPU)
+}
+
+func getPortType(supp uint32) (port string) {
+	for i, p := range [...]string{"tp", "aui", "mii", "fibre", "bnc"} {
+		if supp&(1<<uint(i+7)) > 0 {
+			port += p + "/"
+		}
+	}
+
+	port = strings.TrimRight(port, "/")
+	return
+}
+
+func getPortTypeForGLinkSetting(supp uint8) (port string) {
+	if supp == 0x00 {
+		port = "twisted pair"
+	} else if supp == 0x01 {
+		port = "AUI"
+	} else if supp == 0x02 {
+		port = "media-independent"
+	} else if supp == 0x03 {
+		port = "fibre"
+	} else if supp == 0x04 {
+		port = "BNC"
+	} else if supp == 0x05 {
+		port = "direct attach"
+	} else if supp == 0xef {
+		port = "none"
+	} else if supp == 0xff {
+		port = "other"
+	}
+	return
+}
+
+func getMaxSpeed(supp uint32) (speed uint) {
+	// Fancy, right?
+	switch {
+	case supp&0x78000000 > 0:
+		speed = 56000
+	case supp&0x07800000 > 0:
+		speed = 40000
+	case supp&0x00600000 > 0:
+		speed = 20000
+	case supp&0x001c1000 > 0:
+		speed = 10000
+	case supp&0x00008000 > 0:
+		speed = 2500
+	case supp&0x00020030 > 0:
+		speed = 1000
+	case supp&0x0000000c > 0:
+		speed = 100
+	case supp&0x00000003 > 0:
+		speed = 10
+	}
+
+	return
+}
+
+func getSupported(name string) uint32 {
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_DGRAM, syscall.IPPROTO_IP)
+	if err != nil {
+		return 0
+	}
+	defer syscall.Close(fd)
+
+	// struct ethtool_cmd from /usr/include/linux/ethtool.h
+	var ethtool struct {
+		Cmd           uint32
+		Supported     uint32
+		Advertising   uint32
+		Speed         uint16
+		Duplex        uint8
+		Port          uint8
+		PhyAddress    uint8
+		Transceiver   uint8
+		Autoneg       uint8
+		MdioSupport   uint8
+		Maxtxpkt      uint32
+		Maxrxpkt      uint32
+		SpeedHi       uint16
+		EthTpMdix     uint8
+		Reserved2     uint8
+		LpAdvertising uint32
+		Reserved      [2]uint32
+	}
+
+	// ETHTOOL_GSET from /usr/include/linux/ethtool.h
+	const GSET = 0x1
+
+	ethtool.Cmd = GSET
+
+	// struct ifreq from /usr/include/linux/if.h
+	var ifr struct {
+		Name [16]byte
+		Data uintptr
+	}
+
+	copy(ifr.Name[:], name+"\000")
+	ifr.Data = uintptr(unsafe.Pointer(&ethtool))
+
+	// SIOCETHTOOL from /usr/include/linux/sockios.h
+	const SIOCETHTOOL = 0x8946
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(SIOCETHTOOL), uintptr(unsafe.Pointer(&ifr)))
+	if errno == 0 {
+		return ethtool.Supported
+	}
+
+	return 0
+}
+
+// struct ethtool_cmd from include/uapi/linux/ethtool.h
+type ethtoolLinkSettingType struct {
+	Cmd                 uint32
+	Speed               uint32
+	Duplex              uint8
+	Port                uint8
+	PhyAddress          uint8
+	Autoneg             uint8
+	MdioSupport         uint8
+	EthTpMdix           uint8
+	EthTpMdixCtrl       uint8
+	LinkModeMasksNwords int8
+	Transceiver         uint8
+	Reserved1           [3]uint32
+	Reserved            [7]uint32
+	LinkModeMasks       [0]uint32
+}
+
+func getSupportedWithEthtoolGLinkSetting(name string) (*ethtoolLinkSettingType, error) {
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_DGRAM, syscall.IPPROTO_IP)
+	if err != nil {
+		return nil, fmt.Errorf("error: syscall socket err, %s", err)
+	}
+	defer syscall.Close(fd)
+
+	// ETHTOOL_GLINKSETTINGS from include/uapi/linux/ethtool.h
+	const GLINKSETTING = 0x0000004c
+
+	var ethtoolLinkSetting ethtoolLinkSettingType
+	ethtoolLinkSetting.Cmd = GLINKSETTING
+
+	// struct ifreq from include/uapi/linux/if.h
+	var ifr struct {
+		Name [16]byte
+		Data uintptr
+	}
+
+	copy(ifr.Name[:], name+"\000")
+	ifr.Data = uintptr(unsafe.Pointer(&ethtoolLinkSetting))
+
+	// SIOCETHTOOL from /usr/include/linux/sockios.h
+	const SIOCETHTOOL = 0x8946
+
+	_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(SIOCETHTOOL), uintptr(unsafe.Pointer(&ifr)))
+	if errno == 0 {
+		if ethtoolLinkSetting.LinkModeMasksNwords >= 0 || ethtoolLinkSetting.Cmd != 0x0000004c {
+			return nil, fmt.Errorf("error: link mode mask nwords check, %d", ethtoolLinkSetting.LinkModeMasksNwords)
+		}
+		ethtoolLinkSetting.LinkModeMasksNwords = -ethtoolLinkSetting.LinkModeMasksNwords
+
+		_, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(SIOCETHTOOL), uintptr(unsafe.Pointer(&ifr)))
+		if errno == 0 {
+			return &ethtoolLinkSetting, nil
+		} else {
+			return nil, fmt.Errorf("error: GLinkSetting err with link mode mask, %#v", errno)
+		}
+
+	} else {
+		return nil, fmt.Errorf("error: GLinkSetting err, %#v", errno)
+	}
+	/*
+	   return nil, fmt.Errorf("error: should not reach here, errno, %d", errno)
+	*/
+}
+
+func (si *SysInfo) getNetworkInfo() {
+	sysClassNet := "/sys/class/net"
+	devices, err := ioutil.ReadDir(sysClassNet)
+	if err != nil {
+		return
+	}
+
+	si.Network = make([]NetworkDevice, 0)
+	for _, link := range devices {
+		fullpath := path.Join(sysClassNet, link.Name())
+		_, err := os.Readlink(fullpath)
+		if err != nil {
+			continue
+		}
+
+		/* Use virtual intefaces as well
+		if strings.HasPrefix(dev, "../../devices/virtual/") {
+			continue
+		}
+		*/
+
+		gLinkSetting, err := getSupportedWithEthtoolGLinkSetting(link.Name())
+		var portType string
+		var maxSpeed uint
+
+		if err != nil {
+			// fmt.Printf("err, gLinkSetting, fallback to GSET, err: %s", err)
+
+			supp := getSupported(link.Name())
+			portType = getPortType(supp)
+			maxSpeed = getMaxSpeed(supp)
+		} else {
+			portType = getPortTypeForGLinkSetting(gLinkSetting.Port)
+			maxSpeed = uint(gLinkSetting.Speed)
+		}
+
+		deviceAddresses := []string{}
+		byNameInterface, _ := net.InterfaceByName(link.Name())
+		if err == nil {
+			addresses, _ := byNameInterface.Addrs()
+			for _, v := range addresses {
+				deviceAddresses = append(deviceAddresses, v.String())
+			}
+		}
+
+		device := NetworkDevice{
+			Name:         link.Name(),
+			MACAddress:   slurpFile(path.Join(fullpath, "address")),
+			Port:         portType,
+			Speed:        maxSpeed,
+			CurrentSpeed: readUintFile(path.Join(fullpath, "speed")),
+			Duplex:       slurpFile(path.Join(fullpath, "duplex")),
+			MTU:          readUintFile(path.Join(fullpath, "mtu")),
+			OperState:    slurpFile(path.Join(fullpath, "operstate")),
+			Carrier:      slurpFile(path.Join(fullpath, "carrier")) == "1",
+			IpAddresses:  deviceAddresses,
+			Stats:        readNetworkDeviceStats(fullpath),
+			QueueStats:   getQueueStats(link.Name()),
+		}
+
+		if driver, err := os.Readlink(path.Join(fullpath, "device", "driver")); err == nil {
+			device.Driver = path.Base(driver)
+			device.DriverVersion, device.FirmwareVersion, device.BusInfo = getDriverInfo(link.Name())
+		}
+
+		si.Network = append(si.Network, device)
+	}
+}
+
+func readUintFile(path string) uint {
+	v, err := strconv.ParseUint(slurpFile(path), 10, 64)
+	if err != nil {
+		return 0
+	}
+	return uint(v)
+}
+
+func readNetworkDeviceStats(fullpath string) NetworkDeviceStats {
+	stat := func(name string) uint64 {
+		v, _ := strconv.ParseUint(slurpFile(path.Join(fullpath, "statistics", name)), 10, 64)
+		return v
+	}
+
+	return NetworkDeviceStats{
+		RxBytes:    stat("rx_bytes"),
+		TxBytes:    stat("tx_bytes"),
+		RxPackets:  stat("rx_packets"),
+		TxPackets:  stat("tx_packets"),
+		RxErrors:   stat("rx_errors"),
+		TxErrors:   stat("tx_errors"),
+		RxDropped:  stat("rx_dropped"),
+		TxDropped:  stat("tx_dropped"),
+		Collisions: stat("collisions"),
+		Multicast:  stat("multicast"),
+	}
+}
+
+// ethtoolIoctl issues a SIOCETHTOOL ioctl against the named device, with data
+// pointing at an ethtool_cmd-shaped (or -prefixed) struct.
+func ethtoolIoctl(name string, data unsafe.Pointer) error {
+	fd, err := syscall.Socket(syscall.AF_INET, syscall.SOCK_DGRAM, syscall.IPPROTO_IP)
+	if err != nil {
+		return err
+	}
+	defer syscall.Close(fd)
+
+	var ifr struct {
+		Name [16]byte
+		Data uintptr
+	}
+	copy(ifr.Name[:], name+"\000")
+	ifr.Data = uintptr(data)
+
+	const SIOCETHTOOL = 0x8946
+
+	if _, _, errno := syscall.Syscall(syscall.SYS_IOCTL, uintptr(fd), uintptr(SIOCETHTOOL), uintptr(unsafe.Pointer(&ifr))); errno != 0 {
+		return errno
+	}
+	return nil
+}
+
+// struct ethtool_drvinfo from include/uapi/linux/ethtool.h
+type ethtoolDrvInfo struct {
+	Cmd         uint32
+	Driver      [32]byte
+	Version     [32]byte
+	FwVersion   [32]byte
+	BusInfo     [32]byte
+	EromVersion [32]byte
+	Reserved2   [12]byte
+	NPrivFlags  uint32
+	NStats      uint32
+	TestInfoLen uint32
+	EedumpLen   uint32
+	RegdumpLen  uint32
+}
+
+func getDriverInfo(name string) (version, firmware, busInfo string) {
+	// ETHTOOL_GDRVINFO from include/uapi/linux/ethtool.h
+	const GDRVINFO = 0x00000003
+
+	info := ethtoolDrvInfo{Cmd: GDRVINFO}
+	if err := ethtoolIoctl(name, unsafe.Pointer(&info)); err != nil {
+		return "", "", ""
+	}
+
+	return cString(info.Version[:]), cString(info.FwVersion[:]), cString(info.BusInfo[:])
+}
+
+func cString(b []byte) string {
+	if i := bytes.IndexByte(b, 0); i >= 0 {
+		b = b[:i]
+	}
+	return string(b)
+}
+
+// getQueueStats exposes driver-specific per-queue counters, by first asking
+// how many "stats" strings the driver publishes (ETHTOOL_GSSET_INFO), then
+// fetching their names (ETHTOOL_GSTRINGS) and values (ETHTOOL_GSTATS).
+func getQueueStats(name string) map[string]uint64 {
+	const (
+		ethSSStats       = 1
+		ethtoolGSStrings = 0x0000001b
+		ethtoolGStats    = 0x0000001d
+		ethtoolGSSetInfo = 0x00000037
+		ethGStringLen    = 32
+	)
+
+	ssetInfo := struct {
+		Cmd      uint32
+		Reserved uint32
+		SSetMask uint64
+		Data     uint32
+	}{Cmd: ethtoolGSSetInfo, SSetMask: 1 << ethSSStats}
+
+	if err := ethtoolIoctl(name, unsafe.Pointer(&ssetInfo)); err != nil {
+		return nil
+	}
+
+	n := int(ssetInfo.Data)
+	if n <= 0 {
+		return nil
+	}
+
+	type gstringsHeader struct {
+		Cmd       uint32
+		StringSet uint32
+		Len       uint32
+	}
+
+	gbuf := make([]byte, int(unsafe.Sizeof(gstringsHeader{}))+n*ethGStringLen)
+	ghdr := (*gstringsHeader)(unsafe.Pointer(&gbuf[0]))
+	ghdr.Cmd = ethtoolGSStrings
+	ghdr.StringSet = ethSSStats
+
+	if err := ethtoolIoctl(name, unsafe.Pointer(&gbuf[0])); err != nil {
+		return nil
+	}
+
+	names := make([]string, n)
+	for i := 0; i < n; i++ {
+		start := int(unsafe.Sizeof(gstringsHeader{})) + i*ethGStringLen
+		names[i] = cString(gbuf[start : start+ethGStringLen])
+	}
+
+	type gstatsHeader struct {
+		Cmd    uint32
+		NStats uint32
+	}
+
+	sbuf := make([]byte, int(unsafe.Sizeof(gstatsHeader{}))+n*8)
+	shdr := (*gstatsHeader)(unsafe.Pointer(&sbuf[0]))
+	shdr.Cmd = ethtoolGStats
+	shdr.NStats = uint32(n)
+
+	if err := ethtoolIoctl(name, unsafe.Pointer(&sbuf[0])); err != nil {
+		return nil
+	}
+
+	stats := make(map[string]uint64, n)
+	for i := 0; i < n; i++ {
+		start := int(unsafe.Sizeof(gstatsHeader{})) + i*8
+		stats[names[i]] = *(*uint64)(unsafe.Pointer(&sbuf[start]))
+	}
+
+	return stats
+}
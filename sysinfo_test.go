@@ -0,0 +1,40 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// fakeCollector is a Collector stand-in that records whether it was invoked,
+// so tests can exercise GetInfo/WithCollector without touching the host.
+type fakeCollector struct {
+	cpuCollected     bool
+	networkCollected bool
+}
+
+func (f *fakeCollector) CollectCPU(si *SysInfo) {
+	f.cpuCollected = true
+	si.CPU.Model = "fake CPU"
+}
+
+func (f *fakeCollector) CollectNetwork(si *SysInfo) {
+	f.networkCollected = true
+	si.Network = []NetworkDevice{{Name: "fake0"}}
+}
+
+func TestWithCollector(t *testing.T) {
+	collector := &fakeCollector{}
+
+	si := NewSysInfo(WithCollector(collector))
+	si.GetInfo()
+
+	assert.True(t, collector.cpuCollected, "CollectCPU should have been called")
+	assert.True(t, collector.networkCollected, "CollectNetwork should have been called")
+	assert.Equal(t, "fake CPU", si.CPU.Model, "CPU info from the fake collector didn't land in SysInfo")
+	assert.Equal(t, []NetworkDevice{{Name: "fake0"}}, si.Network, "network info from the fake collector didn't land in SysInfo")
+}
@@ -0,0 +1,85 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+import (
+	"net"
+
+	"github.com/yusufpapurcu/wmi"
+)
+
+// windowsCollector gathers CPU and network information over WMI, the way
+// gopsutil does for Windows hosts.
+type windowsCollector struct{}
+
+func newHostCollector() Collector {
+	return windowsCollector{}
+}
+
+func (windowsCollector) CollectCPU(si *SysInfo) {
+	si.getCPUInfo()
+}
+
+func (windowsCollector) CollectNetwork(si *SysInfo) {
+	si.getNetworkInfo()
+}
+
+type win32Processor struct {
+	Name                      string
+	Manufacturer              string
+	MaxClockSpeed             uint32
+	L2CacheSize               uint32
+	NumberOfProcessors        uint32
+	NumberOfCores             uint32
+	NumberOfLogicalProcessors uint32
+}
+
+func (si *SysInfo) getCPUInfo() {
+	var processors []win32Processor
+	if err := wmi.Query("SELECT Name, Manufacturer, MaxClockSpeed, L2CacheSize, NumberOfCores, NumberOfLogicalProcessors FROM Win32_Processor", &processors); err != nil || len(processors) == 0 {
+		return
+	}
+
+	p := processors[0]
+	si.CPU.Model = p.Name
+	si.CPU.Vendor = p.Manufacturer
+	si.CPU.Speed = uint(p.MaxClockSpeed)
+	si.CPU.Cache = uint(p.L2CacheSize)
+	si.CPU.Cpus = uint(len(processors))
+	si.CPU.Cores = uint(p.NumberOfCores) * si.CPU.Cpus
+	si.CPU.Threads = uint(p.NumberOfLogicalProcessors) * si.CPU.Cpus
+}
+
+type win32NetworkAdapter struct {
+	NetConnectionID string
+	MACAddress      string
+	Speed           uint64
+}
+
+func (si *SysInfo) getNetworkInfo() {
+	var adapters []win32NetworkAdapter
+	if err := wmi.Query("SELECT NetConnectionID, MACAddress, Speed FROM Win32_NetworkAdapter WHERE NetConnectionID IS NOT NULL", &adapters); err != nil {
+		return
+	}
+
+	si.Network = make([]NetworkDevice, 0, len(adapters))
+	for _, a := range adapters {
+		device := NetworkDevice{
+			Name:         a.NetConnectionID,
+			MACAddress:   a.MACAddress,
+			CurrentSpeed: uint(a.Speed / 1000000), // Win32_NetworkAdapter.Speed is the adapter's current, not max, bandwidth
+		}
+
+		if iface, err := net.InterfaceByName(a.NetConnectionID); err == nil {
+			if addrs, err := iface.Addrs(); err == nil {
+				for _, addr := range addrs {
+					device.IpAddresses = append(device.IpAddresses, addr.String())
+				}
+			}
+		}
+
+		si.Network = append(si.Network, device)
+	}
+}
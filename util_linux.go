@@ -0,0 +1,22 @@
+// Copyright © 2016 Zlatko Čalušić
+//
+// Use of this source code is governed by an MIT-style license that can be found in the LICENSE file.
+
+package sysinfo
+
+import (
+	"os"
+	"strings"
+)
+
+// slurpFile reads the named file and returns its contents with surrounding
+// whitespace trimmed, or "" if it can't be read. Most of the sysfs/procfs
+// files this package reads this way are optional, depending on kernel
+// version, driver, or whether a cgroup limit is even in effect.
+func slurpFile(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}